@@ -0,0 +1,357 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// redirectTransport rewrites every request's scheme/host to point at a
+// local httptest.Server, so fetchAccessToken's hardcoded
+// https://www.reddit.com URL (and the API calls AuthenticatedClient
+// issues against it) can be exercised against a fake server instead of
+// the real Reddit API.
+type redirectTransport struct {
+	scheme, host string
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.scheme
+	req.URL.Host = t.host
+	req.Host = t.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestAuthenticatedClient returns an AuthenticatedClient whose requests
+// (token exchange and API calls alike) are all redirected to server.
+func newTestAuthenticatedClient(t *testing.T, server *httptest.Server) *AuthenticatedClient {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+
+	httpClient := &http.Client{Transport: &redirectTransport{scheme: u.Scheme, host: u.Host}}
+	client := NewClient("id", "secret", httpClient)
+	client.skipRateLimiting = true
+	return NewAuthenticatedClient(client, "refresh-token")
+}
+
+const tokenPath = "/api/v1/access_token"
+
+func tokenHandler(w http.ResponseWriter, accessToken string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"access_token":"` + accessToken + `","token_type":"bearer","expires_in":3600}`))
+}
+
+// TestDoRequestRetriesTransientFailures exercises the retry/backoff path
+// directly through doRequest, without going through token exchange.
+func TestDoRequestRetriesTransientFailures(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("x-ratelimit-remaining", "123")
+		w.Header().Set("x-ratelimit-used", "1")
+		w.Header().Set("x-ratelimit-reset", "60")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("id", "secret", server.Client())
+	client.skipRateLimiting = true
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.doRequest(req)
+	if err != nil {
+		t.Fatalf("doRequest returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("server received %d attempts, want 3 (2 failures + 1 success)", got)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if client.RateLimit().Remaining != 123 {
+		t.Errorf("RateLimit().Remaining = %d, want 123", client.RateLimit().Remaining)
+	}
+}
+
+// TestDoRequestPreservesRateLimitOnHeaderlessTransientResponse guards
+// against a transient 5xx with no x-ratelimit-* headers silently wiping
+// out the last known-good RateLimitingInfo.
+func TestDoRequestPreservesRateLimitOnHeaderlessTransientResponse(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			w.Header().Set("x-ratelimit-remaining", "500")
+			w.Header().Set("x-ratelimit-used", "1")
+			w.Header().Set("x-ratelimit-reset", "60")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		// Second request: a bare 500 from e.g. an edge proxy, no headers.
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient("id", "secret", server.Client())
+	client.skipRateLimiting = true
+
+	req1, _ := http.NewRequest("GET", server.URL, nil)
+	resp1, err := client.doRequest(req1)
+	if err != nil {
+		t.Fatalf("first doRequest returned error: %v", err)
+	}
+	resp1.Body.Close()
+	if client.RateLimit().Remaining != 500 {
+		t.Fatalf("setup: RateLimit().Remaining = %d, want 500", client.RateLimit().Remaining)
+	}
+
+	req2, _ := http.NewRequest("GET", server.URL, nil)
+	resp2, err := client.doRequest(req2)
+	if resp2 != nil {
+		resp2.Body.Close()
+	}
+	_ = err // a 500 retried to exhaustion returns the last transient response, not necessarily an error
+
+	if got := client.RateLimit().Remaining; got != 500 {
+		t.Errorf("RateLimit().Remaining = %d after a headerless transient response, want it preserved at 500", got)
+	}
+}
+
+// TestDoRequestExhaustedRetriesReturnsErrRetriesExhausted verifies that
+// giving up after a persistent 5xx/429 is reported distinctly from a
+// transport-level timeout, since callers branch on errors.Is(err,
+// ErrTimeout) to decide whether the request actually timed out.
+func TestDoRequestExhaustedRetriesReturnsErrRetriesExhausted(t *testing.T) {
+	orig := retryBackoff
+	retryBackoff = []time.Duration{time.Millisecond, time.Millisecond}
+	defer func() { retryBackoff = orig }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("id", "secret", server.Client())
+	client.skipRateLimiting = true
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.doRequest(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("doRequest error = %v, want ErrRetriesExhausted", err)
+	}
+	if errors.Is(err, ErrTimeout) {
+		t.Error("a persistent 503 should not be reported as ErrTimeout")
+	}
+}
+
+// TestTokenCachesUntilExpiry verifies token() reuses a cached access
+// token instead of re-authenticating on every call.
+func TestTokenCachesUntilExpiry(t *testing.T) {
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			atomic.AddInt32(&tokenCalls, 1)
+			tokenHandler(w, "tok")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+
+	for i := 0; i < 3; i++ {
+		if _, err := ac.token(); err != nil {
+			t.Fatalf("token() call %d: %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&tokenCalls); got != 1 {
+		t.Errorf("token endpoint called %d times, want 1 (cached token should be reused)", got)
+	}
+}
+
+// TestForceRefreshReAuthenticates verifies forceRefresh discards the
+// cached token so the next token() call re-authenticates.
+func TestForceRefreshReAuthenticates(t *testing.T) {
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			atomic.AddInt32(&tokenCalls, 1)
+			tokenHandler(w, "tok")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+
+	if _, err := ac.token(); err != nil {
+		t.Fatalf("initial token(): %v", err)
+	}
+	ac.forceRefresh()
+	if _, err := ac.token(); err != nil {
+		t.Fatalf("token() after forceRefresh: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&tokenCalls); got != 2 {
+		t.Errorf("token endpoint called %d times, want 2 (forceRefresh should force re-authentication)", got)
+	}
+}
+
+// TestAuthedRequestRetriesOnceOn401 verifies a 401 triggers exactly one
+// forceRefresh-and-retry, succeeding if the retried request comes back
+// clean.
+func TestAuthedRequestRetriesOnceOn401(t *testing.T) {
+	var apiCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			tokenHandler(w, "tok")
+			return
+		}
+		if atomic.AddInt32(&apiCalls, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+	req, _ := http.NewRequest("GET", server.URL+"/r/golang/new.json", nil)
+
+	resp, err := ac.authedRequest(req)
+	if err != nil {
+		t.Fatalf("authedRequest returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&apiCalls); got != 2 {
+		t.Errorf("API endpoint called %d times, want 2 (original + one retry)", got)
+	}
+}
+
+// TestAuthedRequestPersistent401ReturnsErrOauthRevoked verifies a 401
+// that persists across the refresh-and-retry is reported as
+// ErrOauthRevoked rather than a bare status code.
+func TestAuthedRequestPersistent401ReturnsErrOauthRevoked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			tokenHandler(w, "tok")
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+	req, _ := http.NewRequest("GET", server.URL+"/r/golang/new.json", nil)
+
+	_, err := ac.authedRequest(req)
+	if !errors.Is(err, ErrOauthRevoked) {
+		t.Errorf("authedRequest error = %v, want ErrOauthRevoked", err)
+	}
+}
+
+// TestAuthedRequestRetriesOnceOn403 verifies a 403 — ambiguous between a
+// revoked grant and a private subreddit — also gets one
+// forceRefresh-and-retry, succeeding once the refreshed token is accepted.
+func TestAuthedRequestRetriesOnceOn403(t *testing.T) {
+	var apiCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			tokenHandler(w, "tok")
+			return
+		}
+		if atomic.AddInt32(&apiCalls, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+	req, _ := http.NewRequest("GET", server.URL+"/r/golang/new.json", nil)
+
+	resp, err := ac.authedRequest(req)
+	if err != nil {
+		t.Fatalf("authedRequest returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&apiCalls); got != 2 {
+		t.Errorf("API endpoint called %d times, want 2 (original + one retry)", got)
+	}
+}
+
+// TestAuthedRequestPersistentForbiddenMapsToErrSubredditPrivate verifies
+// a 403 that persists across the refresh-and-retry — meaning the token
+// wasn't the problem — falls through to defaultErrorMap as
+// ErrSubredditPrivate.
+func TestAuthedRequestPersistentForbiddenMapsToErrSubredditPrivate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			tokenHandler(w, "tok")
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+	req, _ := http.NewRequest("GET", server.URL+"/r/golang/new.json", nil)
+
+	_, err := ac.authedRequest(req)
+	if !errors.Is(err, ErrSubredditPrivate) {
+		t.Errorf("authedRequest error = %v, want ErrSubredditPrivate", err)
+	}
+}
+
+// TestAuthedRequestMapsNotFound verifies a plain 404 (no ambiguity, no
+// retry) is mapped straight to ErrSubredditNotFound.
+func TestAuthedRequestMapsNotFound(t *testing.T) {
+	var apiCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			tokenHandler(w, "tok")
+			return
+		}
+		atomic.AddInt32(&apiCalls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ac := newTestAuthenticatedClient(t, server)
+	req, _ := http.NewRequest("GET", server.URL+"/r/doesnotexist/new.json", nil)
+
+	_, err := ac.authedRequest(req)
+	if !errors.Is(err, ErrSubredditNotFound) {
+		t.Errorf("authedRequest error = %v, want ErrSubredditNotFound", err)
+	}
+	if got := atomic.LoadInt32(&apiCalls); got != 1 {
+		t.Errorf("API endpoint called %d times, want 1 (404 shouldn't trigger a refresh-and-retry)", got)
+	}
+}
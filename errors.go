@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors returned by Client and AuthenticatedClient methods so
+// callers can react to specific failure modes instead of pattern-matching
+// on log output.
+var (
+	// ErrOauthRevoked means the refresh token no longer works and the
+	// app needs to be re-authorized by the user.
+	ErrOauthRevoked = errors.New("reddit: oauth token revoked or invalid")
+
+	// ErrSubredditNotFound means the subreddit does not exist (or was banned).
+	ErrSubredditNotFound = errors.New("reddit: subreddit not found")
+
+	// ErrSubredditPrivate means the subreddit exists but is private or
+	// quarantined and the current token cannot read it.
+	ErrSubredditPrivate = errors.New("reddit: subreddit is private")
+
+	// ErrTimeout means the request did not complete within the client's
+	// deadline after retries were exhausted.
+	ErrTimeout = errors.New("reddit: request timed out")
+
+	// ErrRetriesExhausted means doRequest gave up after retryBackoff was
+	// exhausted against a persistent transient status (5xx, 429) rather
+	// than a transport-level timeout.
+	ErrRetriesExhausted = errors.New("reddit: retries exhausted")
+)
+
+// defaultErrorMap maps Reddit API status codes to sentinel errors so
+// callers get a typed error instead of a bare *http.Response status.
+var defaultErrorMap = map[int]error{
+	http.StatusUnauthorized: ErrOauthRevoked,
+	http.StatusForbidden:    ErrSubredditPrivate,
+	http.StatusNotFound:     ErrSubredditNotFound,
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// decodeListingResponse decodes a Reddit "Listing" payload (the shape
+// returned by /r/<sub>/new.json and friends) into typed RedditPosts. A
+// malformed child is skipped rather than aborting the whole decode, so one
+// bad post no longer crashes the fetch loop.
+func decodeListingResponse(parser *fastjson.Parser, body []byte) ([]RedditPost, error) {
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("reddit: invalid listing response: %w", err)
+	}
+
+	children := v.GetArray("data", "children")
+	posts := make([]RedditPost, 0, len(children))
+	for _, child := range children {
+		data := child.Get("data")
+		if data == nil {
+			continue
+		}
+		if post, ok := postFromValue(data); ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+// postFromValue extracts a RedditPost from a single listing child's "data"
+// object, skipping the entry if a required field is missing or the wrong
+// type rather than panicking on a bad type assertion.
+func postFromValue(data *fastjson.Value) (RedditPost, bool) {
+	title := getString(data, "title")
+	author := getString(data, "author")
+	if title == "" || author == "" {
+		return RedditPost{}, false
+	}
+
+	return RedditPost{
+		ID:          getString(data, "id"),
+		Title:       title,
+		Author:      author,
+		Upvotes:     getInt(data, "score"),
+		CreatedUTC:  time.Unix(int64(getFloat(data, "created_utc")), 0),
+		Subreddit:   getString(data, "subreddit"),
+		Permalink:   getString(data, "permalink"),
+		NumComments: getInt(data, "num_comments"),
+		Over18:      getBool(data, "over_18"),
+		Thumbnail:   getString(data, "thumbnail"),
+	}, true
+}
+
+func getString(v *fastjson.Value, key string) string {
+	sv := v.Get(key)
+	if sv == nil {
+		return ""
+	}
+	b, err := sv.StringBytes()
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func getInt(v *fastjson.Value, key string) int {
+	iv := v.Get(key)
+	if iv == nil {
+		return 0
+	}
+	n, err := iv.Int()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func getFloat(v *fastjson.Value, key string) float64 {
+	fv := v.Get(key)
+	if fv == nil {
+		return 0
+	}
+	f, err := fv.Float64()
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func getBool(v *fastjson.Value, key string) bool {
+	bv := v.Get(key)
+	if bv == nil {
+		return false
+	}
+	b, err := bv.Bool()
+	if err != nil {
+		return false
+	}
+	return b
+}
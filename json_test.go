@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestDecodeListingResponseSkipsMalformedEntries(t *testing.T) {
+	body := []byte(`{
+		"data": {
+			"children": [
+				{"data": {"title": "good post", "author": "alice", "score": 42}},
+				{"data": {"title": "", "author": "bob", "score": 1}},
+				{"data": {"title": "missing author", "score": 1}},
+				{"kind": "t3"},
+				{"data": {"title": "second good post", "author": "carol", "score": 7}}
+			]
+		}
+	}`)
+
+	var parser fastjson.Parser
+	posts, err := decodeListingResponse(&parser, body)
+	if err != nil {
+		t.Fatalf("decodeListingResponse returned error: %v", err)
+	}
+
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, want 2 (malformed entries should be skipped): %+v", len(posts), posts)
+	}
+	if posts[0].Title != "good post" || posts[0].Author != "alice" {
+		t.Errorf("posts[0] = %+v, want title %q author %q", posts[0], "good post", "alice")
+	}
+	if posts[1].Title != "second good post" || posts[1].Author != "carol" {
+		t.Errorf("posts[1] = %+v, want title %q author %q", posts[1], "second good post", "carol")
+	}
+}
+
+func TestDecodeListingResponseInvalidJSON(t *testing.T) {
+	var parser fastjson.Parser
+	if _, err := decodeListingResponse(&parser, []byte("not json")); err == nil {
+		t.Fatal("expected an error decoding invalid JSON, got nil")
+	}
+}
+
+func TestPostFromValueRequiresTitleAndAuthor(t *testing.T) {
+	var parser fastjson.Parser
+
+	v, err := parser.Parse(`{"title": "hello", "author": "dave", "score": 3}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	post, ok := postFromValue(v)
+	if !ok {
+		t.Fatal("expected ok=true for a complete entry")
+	}
+	if post.Title != "hello" || post.Author != "dave" || post.Upvotes != 3 {
+		t.Errorf("post = %+v, want Title=hello Author=dave Upvotes=3", post)
+	}
+
+	v, err = parser.Parse(`{"author": "dave"}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, ok := postFromValue(v); ok {
+		t.Error("expected ok=false when title is missing")
+	}
+
+	v, err = parser.Parse(`{"title": "hello"}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, ok := postFromValue(v); ok {
+		t.Error("expected ok=false when author is missing")
+	}
+}
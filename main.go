@@ -1,22 +1,33 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // RedditPost structure to hold post data
 type RedditPost struct {
-	Title   string `json:"title"`
-	Author  string `json:"author"`
-	Upvotes int    `json:"score"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Author      string    `json:"author"`
+	Upvotes     int       `json:"score"`
+	CreatedUTC  time.Time `json:"created_utc"`
+	Subreddit   string    `json:"subreddit"`
+	Permalink   string    `json:"permalink"`
+	NumComments int       `json:"num_comments"`
+	Over18      bool      `json:"over_18"`
+	Thumbnail   string    `json:"thumbnail"`
 }
 
 // Stats structure to hold statistics
@@ -27,48 +38,12 @@ type Stats struct {
 
 // StatsManager manages stats safely and keeps track of rate limiting
 type StatsManager struct {
-	mu                sync.Mutex
-	stats             Stats
-	remainingRequests int
-	resetTime         time.Time
+	mu        sync.Mutex
+	stats     Stats
+	rateLimit RateLimitingInfo
+	sinks     []Sink
 }
 
-// FetchAccessToken retrieves an OAuth2 access token from Reddit
-// FetchAccessToken retrieves an OAuth2 access token from Reddit
-func FetchAccessToken(client *http.Client, clientID, clientSecret string) (string, error) {
-	url := "https://www.reddit.com/api/v1/access_token"
-	form := "grant_type=client_credentials"
-
-	// Create the request with the body
-	req, err := http.NewRequest("POST", url, io.NopCloser(strings.NewReader(form)))
-	if err != nil {
-		return "", err
-	}
-	req.SetBasicAuth(clientID, clientSecret)
-	req.Header.Set("User-Agent", "RedditFetcherCLI")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.ContentLength = int64(len(form)) // Set Content-Length
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to get access token: %s", resp.Status)
-	}
-
-	var tokenResponse map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		return "", err
-	}
-
-	return tokenResponse["access_token"].(string), nil
-}
-
-	
-
 // NewStatsManager creates a new StatsManager
 func NewStatsManager() *StatsManager {
 	return &StatsManager{
@@ -78,11 +53,18 @@ func NewStatsManager() *StatsManager {
 	}
 }
 
-// UpdateStats updates the stats based on fetched posts
-func (sm *StatsManager) UpdateStats(posts []RedditPost) {
+// AddSink registers a Sink to receive every future stats snapshot and
+// fetched post.
+func (sm *StatsManager) AddSink(s Sink) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
+	sm.sinks = append(sm.sinks, s)
+}
 
+// UpdateStats updates the stats based on fetched posts and fans the
+// posts and the resulting snapshot out to every registered Sink.
+func (sm *StatsManager) UpdateStats(ctx context.Context, posts []RedditPost) {
+	sm.mu.Lock()
 	for _, post := range posts {
 		sm.stats.TopUsers[post.Author]++
 
@@ -90,6 +72,43 @@ func (sm *StatsManager) UpdateStats(posts []RedditPost) {
 			sm.stats.MostUpvotedPost = post
 		}
 	}
+	snapshot := sm.snapshotLocked()
+	sinks := append([]Sink(nil), sm.sinks...)
+	sm.mu.Unlock()
+
+	for _, sink := range sinks {
+		for _, post := range posts {
+			if err := sink.EmitPost(ctx, post); err != nil {
+				log.Println("sink: failed to emit post:", err)
+			}
+		}
+		if err := sink.Emit(ctx, snapshot); err != nil {
+			log.Println("sink: failed to emit stats snapshot:", err)
+		}
+	}
+}
+
+// snapshotLocked copies the current stats into a StatsSnapshot. Callers
+// must hold sm.mu.
+func (sm *StatsManager) snapshotLocked() StatsSnapshot {
+	topUsers := make(map[string]int, len(sm.stats.TopUsers))
+	for user, count := range sm.stats.TopUsers {
+		topUsers[user] = count
+	}
+	return StatsSnapshot{
+		TopUsers:        topUsers,
+		MostUpvotedPost: sm.stats.MostUpvotedPost,
+		RateLimit:       sm.rateLimit,
+	}
+}
+
+// UpdateRateLimit records the rate limit state observed on the most
+// recent request. The pipeline (Client.doRequest) parses the headers;
+// StatsManager just stores the typed result.
+func (sm *StatsManager) UpdateRateLimit(info RateLimitingInfo) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.rateLimit = info
 }
 
 // PrintStats prints the current statistics and rate limit info
@@ -110,78 +129,123 @@ func (sm *StatsManager) PrintStats() {
 	}
 
 	// Print rate limit info if available
-	if sm.remainingRequests > 0 {
-		fmt.Printf("Remaining requests: %d\n", sm.remainingRequests)
-		if !sm.resetTime.IsZero() {
-			countdown := time.Until(sm.resetTime).Seconds()
+	if sm.rateLimit.Remaining > 0 {
+		fmt.Printf("Remaining requests: %d\n", sm.rateLimit.Remaining)
+		if !sm.rateLimit.Reset.IsZero() {
+			countdown := time.Until(sm.rateLimit.Reset).Seconds()
 			fmt.Printf("Rate limit will reset in: %.0f seconds\n", countdown)
 		}
 	} else {
 		fmt.Println("Rate limit exceeded. Please wait for reset.")
-		if !sm.resetTime.IsZero() {
-			countdown := time.Until(sm.resetTime).Seconds()
+		if !sm.rateLimit.Reset.IsZero() {
+			countdown := time.Until(sm.rateLimit.Reset).Seconds()
 			fmt.Printf("You can resume requests in: %.0f seconds\n", countdown)
 		}
 	}
 	fmt.Println() // Print an empty line for better readability
 }
 
-func FetchRedditData(client *http.Client, subreddit string, sm *StatsManager, token string, wg *sync.WaitGroup) {
+// FetchRedditData is a thin wrapper around AuthenticatedClient.SubredditNew
+// that feeds the result into the shared StatsManager and, if wm is
+// non-nil, runs every fetched post through the registered watchers.
+func FetchRedditData(ctx context.Context, ac *AuthenticatedClient, subreddit string, sm *StatsManager, wm *WatcherManager, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	url := "https://www.reddit.com/r/" + subreddit + "/new.json?limit=10"
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("Authorization", "Bearer "+token) // Add token here
-	req.Header.Set("User-Agent", "RedditFetcherCLI")
-
-	// Make the request
-	resp, err := client.Do(req)
+	posts, err := ac.SubredditNew(ctx, subreddit)
 	if err != nil {
 		log.Println("Error fetching data:", err)
 		return
 	}
-	defer resp.Body.Close()
 
-	// Update remaining requests and reset time based on headers
-	if resp.StatusCode == 429 {
-		log.Println("Rate limit exceeded. Waiting...")
-		return
+	sm.UpdateRateLimit(ac.RateLimit())
+
+	log.Println("Fetching data from subreddit:", subreddit)
+	fmt.Println("Remaining requests:", ac.RateLimit().Remaining)
+
+	sm.UpdateStats(ctx, posts) // Update stats with the fetched posts
+
+	if wm != nil {
+		wm.Run(posts)
 	}
+}
 
-	// Check headers for rate limit information
-	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
-		sm.mu.Lock()
-		defer sm.mu.Unlock()
-		fmt.Sscanf(remaining, "%d", &sm.remainingRequests)
-		fmt.Println("Remaining Requests:", sm.remainingRequests)
+// setupWatcherFromEnv registers a single Watcher configured from
+// WATCH_SUBREDDIT/WATCH_KEYWORDS/WATCH_MIN_UPVOTES against a new
+// WatcherManager, or returns nil if WATCH_SUBREDDIT isn't set. Matches
+// are logged; wiring a Watcher's Callback to a Sink or webhook instead is
+// left to callers that need more than a log line.
+func setupWatcherFromEnv(ctx context.Context, ac *AuthenticatedClient) *WatcherManager {
+	subreddit := os.Getenv("WATCH_SUBREDDIT")
+	if subreddit == "" {
+		return nil
 	}
 
-	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
-		resetUnix, _ := strconv.ParseInt(reset, 10, 64)
-		sm.resetTime = time.Unix(resetUnix, 0)
-		fmt.Println("Rate Limit Reset Time:", sm.resetTime)
+	w := &Watcher{
+		Subreddit: subreddit,
+		Callback: func(post RedditPost) {
+			log.Printf("watch match in /r/%s: %q by %s (%d upvotes) %s\n", post.Subreddit, post.Title, post.Author, post.Upvotes, post.Permalink)
+		},
+	}
+	if kws := os.Getenv("WATCH_KEYWORDS"); kws != "" {
+		w.KeywordsInclude = strings.Split(kws, ",")
+	}
+	if v := os.Getenv("WATCH_MIN_UPVOTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			w.MinUpvotes = n
+		}
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Println("Error decoding response:", err)
-		return
+	wm := NewWatcherManager(1024)
+	if err := wm.Register(ctx, ac, w); err != nil {
+		log.Println("watcher: failed to register WATCH_SUBREDDIT:", err)
+		return nil
 	}
+	return wm
+}
 
-	var posts []RedditPost
-	for _, child := range result["data"].(map[string]interface{})["children"].([]interface{}) {
-		postData := child.(map[string]interface{})["data"].(map[string]interface{})
-		posts = append(posts, RedditPost{
-			Title:  postData["title"].(string),
-			Author: postData["author"].(string),
-			Upvotes: int(postData["score"].(float64)),
-		})
+// setupSinksFromEnv registers a Sink on sm for each of SINK_NDJSON_PATH,
+// SINK_CSV_PATH, SINK_WEBHOOK_URL, and SINK_SQLITE_PATH that's set, so the
+// binary can feed dashboards or downstream pipelines alongside (or instead
+// of) the stdout stats printer. A sink that fails to open is logged and
+// skipped rather than aborting startup.
+func setupSinksFromEnv(sm *StatsManager) {
+	if path := os.Getenv("SINK_NDJSON_PATH"); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Println("sink: failed to open SINK_NDJSON_PATH:", err)
+		} else {
+			sm.AddSink(NewNDJSONSink(f))
+		}
 	}
 
-	log.Println("Fetching data from subreddit:", subreddit)
-	fmt.Println("Remaining requests:", sm.remainingRequests)
+	if path := os.Getenv("SINK_CSV_PATH"); path != "" {
+		info, statErr := os.Stat(path)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Println("sink: failed to open SINK_CSV_PATH:", err)
+		} else {
+			sink := NewCSVSink(f)
+			if statErr == nil && info.Size() > 0 {
+				// Appending to a file that already has rows: don't write
+				// a second header line into the middle of it.
+				sink.wroteHeader = true
+			}
+			sm.AddSink(sink)
+		}
+	}
+
+	if url := os.Getenv("SINK_WEBHOOK_URL"); url != "" {
+		sm.AddSink(NewWebhookSink(url, &http.Client{}))
+	}
 
-	sm.UpdateStats(posts) // Update stats with the fetched posts
+	if path := os.Getenv("SINK_SQLITE_PATH"); path != "" {
+		sink, err := NewSQLiteSink(path)
+		if err != nil {
+			log.Println("sink: failed to open SINK_SQLITE_PATH:", err)
+		} else {
+			sm.AddSink(sink)
+		}
+	}
 }
 
 // Stats printing loop
@@ -194,46 +258,58 @@ func StartStatsPrinting(sm *StatsManager, interval time.Duration) {
 
 // Main function
 func main() {
-	client := &http.Client{}
-
-	clientID := "ey9AWAIGj6rn18bQIecGRw"     // Replace with your client ID
-	clientSecret := "HcUzeI3XIHCYGeDjx3DIE_mwX9doGA" // Replace with your client secret
+	clientID := os.Getenv("REDDIT_CLIENT_ID")
+	clientSecret := os.Getenv("REDDIT_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		log.Fatal("REDDIT_CLIENT_ID and REDDIT_CLIENT_SECRET must be set")
+	}
 
-	// Fetch access token
-	token, err := FetchAccessToken(client, clientID, clientSecret)
-	if err != nil {
-		log.Fatal("Error fetching access token:", err)
-		return
+	var clientOpts []ClientOption
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		clientOpts = append(clientOpts, WithRedis(redis.NewClient(&redis.Options{Addr: addr})))
+	}
+	if os.Getenv("METRICS_LOG") != "" {
+		clientOpts = append(clientOpts, WithMetrics(logMetrics{}))
 	}
+	if os.Getenv("OTEL_TRACE_STDOUT") != "" {
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			log.Println("observability: failed to create stdout trace exporter:", err)
+		} else {
+			tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+			clientOpts = append(clientOpts, WithTracer(tp.Tracer("redditFetcher")))
+		}
+	}
+
+	client := NewClient(clientID, clientSecret, &http.Client{}, clientOpts...)
+	ac := NewAuthenticatedClient(client, os.Getenv("REDDIT_REFRESH_TOKEN"))
 
 	// Create a StatsManager instance
 	sm := NewStatsManager()
+	setupSinksFromEnv(sm)
 
 	// Set the subreddit to fetch data from
 	subreddit := "golang" // Replace with your chosen subreddit
 
+	// Optionally watch a subreddit for posts matching WATCH_KEYWORDS/
+	// WATCH_MIN_UPVOTES, logging every match. wm is nil (and FetchRedditData
+	// skips watcher dispatch) unless WATCH_SUBREDDIT is set.
+	wm := setupWatcherFromEnv(context.Background(), ac)
+
 	// Start a Goroutine to print stats every 10 seconds
 	go StartStatsPrinting(sm, 10*time.Second)
 
 	// Rate limit interval (e.g., 2 seconds)
 	limitInterval := 5 * time.Second
 
-	// Set up a loop to continuously fetch data
+	// Set up a loop to continuously fetch data. Client.doRequest already
+	// waits out the rate-limit window and retries transient failures, so
+	// the loop itself just paces requests.
 	for {
-		// Rate limiting: Check remaining requests before fetching
-		if sm.remainingRequests > 0 {
-			wg := sync.WaitGroup{}
-			wg.Add(1)
-			go FetchRedditData(client, subreddit, sm, token, &wg) // Pass token here
-			wg.Wait() // Wait for the fetching to complete
-		} else {
-			// Wait for the rate limit to reset
-			countdown := time.Until(sm.resetTime).Seconds()
-			if countdown > 0 {
-				fmt.Printf("Waiting for %.0f seconds before making a new request...\n", countdown)
-				time.Sleep(time.Duration(countdown) * time.Second)
-			}
-		}
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go FetchRedditData(context.Background(), ac, subreddit, sm, wm, &wg)
+		wg.Wait() // Wait for the fetching to complete
 
 		// Wait before the next request
 		time.Sleep(limitInterval) // Adjust based on your needs
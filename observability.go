@@ -0,0 +1,115 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics is the subset of a statsd client Client uses to report request
+// pipeline metrics. The zero value (noopMetrics) discards everything, so
+// callers who don't opt in via WithMetrics pay nothing.
+type Metrics interface {
+	Incr(name string, tags ...string)
+	Histogram(name string, value float64, tags ...string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Incr(string, ...string)               {}
+func (noopMetrics) Histogram(string, float64, ...string) {}
+
+// logMetrics is a Metrics implementation that writes each call to the
+// standard logger. It's meant for local debugging (METRICS_LOG=1) where
+// wiring up a real statsd client isn't worth it.
+type logMetrics struct{}
+
+func (logMetrics) Incr(name string, tags ...string) {
+	log.Printf("metric: incr %s %v", name, tags)
+}
+
+func (logMetrics) Histogram(name string, value float64, tags ...string) {
+	log.Printf("metric: histogram %s=%f %v", name, value, tags)
+}
+
+// WithMetrics reports request pipeline metrics — connection reuse
+// (reddit.api.connections.reused/.new), latency (reddit.api.latency),
+// retries (reddit.api.retries), and errors tagged by status code
+// (reddit.api.errors) — to m.
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) { c.metrics = m }
+}
+
+// WithTracer starts an OpenTelemetry span per request using tracer,
+// tagged with the subreddit, endpoint, and rate-limit remaining. Without
+// this option Client uses trace.NewNoopTracerProvider(), so tracing is a
+// no-op until a caller opts in.
+func WithTracer(tracer trace.Tracer) ClientOption {
+	return func(c *Client) { c.tracer = tracer }
+}
+
+// traceRequest wraps req's context with an OpenTelemetry span and an
+// httptrace.ClientTrace that reports connection reuse, then runs fn
+// (doRequest's retry loop) and records latency, status, and errors.
+func (c *Client) traceRequest(req *http.Request, fn func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	endpoint := req.URL.Path
+	subreddit := subredditFromPath(endpoint)
+
+	ctx, span := c.tracer.Start(req.Context(), "reddit.api."+req.Method, trace.WithAttributes(
+		attribute.String("reddit.endpoint", endpoint),
+		attribute.String("reddit.subreddit", subreddit),
+	))
+	defer span.End()
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				c.metrics.Incr("reddit.api.connections.reused", "endpoint:"+endpoint)
+			} else {
+				c.metrics.Incr("reddit.api.connections.new", "endpoint:"+endpoint)
+			}
+		},
+	})
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	resp, err := fn(req)
+	c.metrics.Histogram("reddit.api.latency", time.Since(start).Seconds(), "endpoint:"+endpoint)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		c.metrics.Incr("reddit.api.errors", "endpoint:"+endpoint, "status:transport")
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("reddit.ratelimit.remaining", c.RateLimit().Remaining))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+		c.metrics.Incr("reddit.api.errors", "endpoint:"+endpoint, "status:"+strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// subredditFromPath extracts the subreddit name from a Reddit API path
+// like "/r/golang/new.json", for tagging traces and metrics. Paths that
+// don't match the /r/<name>/... shape return "".
+func subredditFromPath(path string) string {
+	const prefix = "/r/"
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	rest := path[len(prefix):]
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		return rest[:i]
+	}
+	return rest
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSubredditFromPath(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/r/golang/new.json", "golang"},
+		{"/r/golang/about.json", "golang"},
+		{"/r/golang", "golang"},
+		{"/api/v1/access_token", ""},
+		{"/", ""},
+	}
+	for _, tc := range cases {
+		if got := subredditFromPath(tc.path); got != tc.want {
+			t.Errorf("subredditFromPath(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+type countingMetrics struct {
+	incrs map[string]int
+	hists map[string]float64
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{incrs: make(map[string]int), hists: make(map[string]float64)}
+}
+
+func (m *countingMetrics) Incr(name string, tags ...string) {
+	m.incrs[name]++
+}
+
+func (m *countingMetrics) Histogram(name string, value float64, tags ...string) {
+	m.hists[name] = value
+}
+
+func TestTraceRequestRecordsSuccessfulSpanAndMetrics(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	metrics := newCountingMetrics()
+
+	c := &Client{metrics: metrics, tracer: tp.Tracer("test")}
+
+	req, _ := http.NewRequest("GET", "https://oauth.reddit.com/r/golang/new.json", nil)
+	resp, err := c.traceRequest(req, func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatalf("traceRequest returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("resp.StatusCode = %d, want 200", resp.StatusCode)
+	}
+
+	if metrics.incrs["reddit.api.errors"] != 0 {
+		t.Errorf("expected no error metric on a 200 response, got %d", metrics.incrs["reddit.api.errors"])
+	}
+	if _, ok := metrics.hists["reddit.api.latency"]; !ok {
+		t.Error("expected reddit.api.latency to be recorded")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "reddit.api.GET" {
+		t.Errorf("span name = %q, want %q", span.Name, "reddit.api.GET")
+	}
+
+	var sawSubreddit bool
+	for _, attr := range span.Attributes {
+		if attr.Key == "reddit.subreddit" && attr.Value.AsString() == "golang" {
+			sawSubreddit = true
+		}
+	}
+	if !sawSubreddit {
+		t.Errorf("expected span to carry reddit.subreddit=golang, got %+v", span.Attributes)
+	}
+}
+
+func TestTraceRequestRecordsErrorStatus(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	metrics := newCountingMetrics()
+
+	c := &Client{metrics: metrics, tracer: tp.Tracer("test")}
+
+	req, _ := http.NewRequest("GET", "https://oauth.reddit.com/r/golang/new.json", nil)
+	resp, err := c.traceRequest(req, func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error", Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatalf("traceRequest returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("resp.StatusCode = %d, want 500", resp.StatusCode)
+	}
+
+	if metrics.incrs["reddit.api.errors"] != 1 {
+		t.Errorf("expected one reddit.api.errors increment for a 500 response, got %d", metrics.incrs["reddit.api.errors"])
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("span status code = %v, want Error", spans[0].Status.Code)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitingInfo is Reddit's per-app rate limit state as reported on
+// the x-ratelimit-* response headers. It is JSON-tagged so it can be
+// written to and read from Redis for fleet-wide coordination.
+type RateLimitingInfo struct {
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+}
+
+// RequestRemainingBuffer is the number of requests remaining in the
+// current window below which doRequest proactively waits for the reset
+// instead of racing the last few requests against a 429.
+const RequestRemainingBuffer = 50
+
+// retryBackoff is the bounded backoff schedule used for transient
+// failures (5xx, 429, timeouts) before doRequest gives up.
+var retryBackoff = []time.Duration{200 * time.Millisecond, 500 * time.Millisecond, 1 * time.Second, 2 * time.Second}
+
+// SkipRateLimiting is a sentinel refresh token that disables rate-limit
+// throttling entirely. Pass it to NewAuthenticatedClient in tests that
+// hit a local httptest.Server, which never sends real rate-limit headers.
+const SkipRateLimiting = "skip-rate-limiting"
+
+// parseRateLimitingInfo reads the x-ratelimit-* headers Reddit attaches to
+// every API response. ok is false if none of the headers were present
+// (e.g. a 5xx from an edge proxy that never reached Reddit's app tier),
+// so callers can avoid clobbering the last known-good RateLimitingInfo
+// with a meaningless zero value.
+func parseRateLimitingInfo(h http.Header) (info RateLimitingInfo, ok bool) {
+	if v := h.Get("x-ratelimit-remaining"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			info.Remaining = int(f)
+			ok = true
+		}
+	}
+	if v := h.Get("x-ratelimit-used"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			info.Used = int(f)
+			ok = true
+		}
+	}
+	if v := h.Get("x-ratelimit-reset"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			info.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+			ok = true
+		}
+	}
+	return info, ok
+}
+
+// isTransient reports whether a request can be retried: 5xx, 429, or a
+// transport-level timeout awaiting response headers.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return strings.Contains(err.Error(), "timeout awaiting response headers")
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
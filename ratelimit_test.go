@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseRateLimitingInfo(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-ratelimit-remaining", "599.0")
+	h.Set("x-ratelimit-used", "1.0")
+	h.Set("x-ratelimit-reset", "300")
+
+	info, ok := parseRateLimitingInfo(h)
+	if !ok {
+		t.Fatal("expected ok=true when rate-limit headers are present")
+	}
+	if info.Remaining != 599 {
+		t.Errorf("Remaining = %d, want 599", info.Remaining)
+	}
+	if info.Used != 1 {
+		t.Errorf("Used = %d, want 1", info.Used)
+	}
+	if info.Reset.IsZero() {
+		t.Error("Reset should be set when x-ratelimit-reset is present")
+	}
+}
+
+func TestParseRateLimitingInfoMissingHeaders(t *testing.T) {
+	info, ok := parseRateLimitingInfo(http.Header{})
+	if ok {
+		t.Fatal("expected ok=false when no rate-limit headers are present")
+	}
+	if info != (RateLimitingInfo{}) {
+		t.Errorf("expected zero-value RateLimitingInfo, got %+v", info)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"429", &http.Response{StatusCode: http.StatusTooManyRequests}, nil, true},
+		{"500", &http.Response{StatusCode: http.StatusInternalServerError}, nil, true},
+		{"503", &http.Response{StatusCode: http.StatusServiceUnavailable}, nil, true},
+		{"200", &http.Response{StatusCode: http.StatusOK}, nil, false},
+		{"404", &http.Response{StatusCode: http.StatusNotFound}, nil, false},
+		{"timeout", nil, errors.New("http2: timeout awaiting response headers"), true},
+		{"other transport error", nil, errors.New("connection refused"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTransient(tc.resp, tc.err); got != tc.want {
+				t.Errorf("isTransient() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffSchedule(t *testing.T) {
+	if len(retryBackoff) != 4 {
+		t.Fatalf("len(retryBackoff) = %d, want 4", len(retryBackoff))
+	}
+	for i := 1; i < len(retryBackoff); i++ {
+		if retryBackoff[i] <= retryBackoff[i-1] {
+			t.Errorf("retryBackoff[%d] = %v is not greater than retryBackoff[%d] = %v", i, retryBackoff[i], i-1, retryBackoff[i-1])
+		}
+	}
+}
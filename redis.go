@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// rateLimitKey is the Redis key fleet-wide rate limit state is stored
+// under, keyed by OAuth client ID so multiple apps sharing one Redis
+// instance don't collide.
+func rateLimitKey(clientID string) string {
+	return fmt.Sprintf("reddit:%s:ratelimited", clientID)
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// WithRedis enables distributed rate-limit coordination: after each request
+// Client writes the observed RateLimitingInfo to Redis with a TTL equal to
+// the reset window, and before each request it checks that key and blocks
+// until the TTL expires if the fleet-wide buffer is low. This lets multiple
+// redditFetcher instances sharing one OAuth app avoid collectively blowing
+// Reddit's quota. Single-process callers that don't pass WithRedis pay
+// nothing — doRequest falls back to the RateLimitingInfo it observed
+// locally on its own last request.
+func WithRedis(rdb *redis.Client) ClientOption {
+	return func(c *Client) {
+		c.redis = rdb
+	}
+}
+
+// publishRateLimit writes info to Redis with a TTL matching its reset
+// window so other fleet members see it. A stale or zero Reset is not
+// published since there would be nothing meaningful to TTL it with.
+func (c *Client) publishRateLimit(ctx context.Context, info RateLimitingInfo) {
+	ttl := time.Until(info.Reset)
+	if info.Reset.IsZero() || ttl <= 0 {
+		return
+	}
+
+	payload, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	if err := c.redis.Set(ctx, rateLimitKey(c.id), payload, ttl).Err(); err != nil {
+		log.Println("redis: failed to publish rate limit:", err)
+	}
+}
+
+// fleetRateLimit reads the last RateLimitingInfo any fleet member
+// published, along with the key's remaining TTL. ok is false if no other
+// member has published yet.
+func (c *Client) fleetRateLimit(ctx context.Context) (info RateLimitingInfo, ttl time.Duration, ok bool) {
+	key := rateLimitKey(c.id)
+
+	val, err := c.redis.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return RateLimitingInfo{}, 0, false
+	}
+	if err != nil {
+		log.Println("redis: failed to read rate limit:", err)
+		return RateLimitingInfo{}, 0, false
+	}
+	if err := json.Unmarshal([]byte(val), &info); err != nil {
+		return RateLimitingInfo{}, 0, false
+	}
+
+	if ttl, err = c.redis.TTL(ctx, key).Result(); err != nil {
+		ttl = 0
+	}
+	return info, ttl, true
+}
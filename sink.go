@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Sink receives stats snapshots and individual posts as they're fetched,
+// letting redditFetcher feed dashboards or downstream pipelines instead of
+// only printing to stdout.
+type Sink interface {
+	Emit(ctx context.Context, snapshot StatsSnapshot) error
+	EmitPost(ctx context.Context, post RedditPost) error
+}
+
+// StatsSnapshot is an immutable copy of StatsManager's state at the
+// moment its sinks are notified.
+type StatsSnapshot struct {
+	TopUsers        map[string]int
+	MostUpvotedPost RedditPost
+	RateLimit       RateLimitingInfo
+}
+
+// NDJSONSink writes one JSON object per line to w: stats snapshots and
+// posts are interleaved in the order Emit/EmitPost are called.
+type NDJSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONSink creates a Sink that writes newline-delimited JSON to w.
+func NewNDJSONSink(w io.Writer) *NDJSONSink {
+	return &NDJSONSink{w: w}
+}
+
+func (s *NDJSONSink) Emit(_ context.Context, snapshot StatsSnapshot) error {
+	return s.writeLine(snapshot)
+}
+
+func (s *NDJSONSink) EmitPost(_ context.Context, post RedditPost) error {
+	return s.writeLine(post)
+}
+
+func (s *NDJSONSink) writeLine(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.NewEncoder(s.w).Encode(v)
+}
+
+// CSVSink writes posts as CSV rows to w. Stats snapshots aren't tabular
+// and are silently ignored.
+type CSVSink struct {
+	mu          sync.Mutex
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVSink creates a Sink that writes posts as CSV rows to w.
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w)}
+}
+
+func (s *CSVSink) Emit(context.Context, StatsSnapshot) error {
+	return nil
+}
+
+func (s *CSVSink) EmitPost(_ context.Context, post RedditPost) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.wroteHeader {
+		header := []string{"id", "title", "author", "score", "num_comments", "subreddit", "permalink", "created_utc"}
+		if err := s.w.Write(header); err != nil {
+			return err
+		}
+		s.wroteHeader = true
+	}
+
+	row := []string{
+		post.ID,
+		post.Title,
+		post.Author,
+		strconv.Itoa(post.Upvotes),
+		strconv.Itoa(post.NumComments),
+		post.Subreddit,
+		post.Permalink,
+		strconv.FormatInt(post.CreatedUTC.Unix(), 10),
+	}
+	if err := s.w.Write(row); err != nil {
+		return err
+	}
+
+	s.w.Flush()
+	return s.w.Error()
+}
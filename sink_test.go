@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNDJSONSinkWritesOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewNDJSONSink(&buf)
+	ctx := context.Background()
+
+	post := RedditPost{ID: "abc", Title: "hello", Author: "alice", Upvotes: 5}
+	snapshot := StatsSnapshot{TopUsers: map[string]int{"alice": 1}}
+
+	if err := sink.EmitPost(ctx, post); err != nil {
+		t.Fatalf("EmitPost: %v", err)
+	}
+	if err := sink.Emit(ctx, snapshot); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+
+	var gotPost RedditPost
+	if err := json.Unmarshal([]byte(lines[0]), &gotPost); err != nil {
+		t.Fatalf("unmarshal post line: %v", err)
+	}
+	if gotPost.ID != "abc" || gotPost.Title != "hello" {
+		t.Errorf("decoded post = %+v, want ID=abc Title=hello", gotPost)
+	}
+
+	var gotSnapshot StatsSnapshot
+	if err := json.Unmarshal([]byte(lines[1]), &gotSnapshot); err != nil {
+		t.Fatalf("unmarshal snapshot line: %v", err)
+	}
+	if gotSnapshot.TopUsers["alice"] != 1 {
+		t.Errorf("decoded snapshot TopUsers = %+v, want alice:1", gotSnapshot.TopUsers)
+	}
+}
+
+func TestCSVSinkWritesHeaderOnceThenRows(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewCSVSink(&buf)
+	ctx := context.Background()
+
+	posts := []RedditPost{
+		{ID: "a", Title: "first", Author: "alice", Upvotes: 1, Subreddit: "golang"},
+		{ID: "b", Title: "second", Author: "bob", Upvotes: 2, Subreddit: "golang"},
+	}
+	for _, p := range posts {
+		if err := sink.EmitPost(ctx, p); err != nil {
+			t.Fatalf("EmitPost: %v", err)
+		}
+	}
+	// Emit on a snapshot should be a no-op (stats aren't tabular).
+	if err := sink.Emit(ctx, StatsSnapshot{}); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (1 header + 2 rows): %q", len(lines), buf.String())
+	}
+	if lines[0] != "id,title,author,score,num_comments,subreddit,permalink,created_utc" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "a,first,alice,1,") {
+		t.Errorf("row 1 = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "b,second,bob,2,") {
+		t.Errorf("row 2 = %q", lines[2])
+	}
+}
+
+func TestWebhookSinkRetriesServerErrorsThenSucceeds(t *testing.T) {
+	orig := retryBackoff
+	retryBackoff = []time.Duration{time.Millisecond, time.Millisecond}
+	defer func() { retryBackoff = orig }()
+
+	var attempts int32
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		gotBody = buf.Bytes()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, server.Client())
+	post := RedditPost{ID: "xyz", Title: "hi"}
+	if err := sink.EmitPost(context.Background(), post); err != nil {
+		t.Fatalf("EmitPost: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("server received %d attempts, want 3 (2 failures + 1 success)", got)
+	}
+	var decoded RedditPost
+	if err := json.Unmarshal(gotBody, &decoded); err != nil {
+		t.Fatalf("unmarshal posted body: %v", err)
+	}
+	if decoded.ID != "xyz" {
+		t.Errorf("posted body ID = %q, want xyz", decoded.ID)
+	}
+}
+
+func TestWebhookSinkReturnsErrorOnClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, server.Client())
+	if err := sink.EmitPost(context.Background(), RedditPost{ID: "xyz"}); err == nil {
+		t.Fatal("expected an error for a 400 response, got nil")
+	}
+}
+
+func TestSQLiteSinkPersistsPostsAndSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink.db")
+	sink, err := NewSQLiteSink(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteSink: %v", err)
+	}
+	defer sink.Close()
+
+	ctx := context.Background()
+	post := RedditPost{ID: "abc", Title: "hello", Author: "alice", Upvotes: 5, Subreddit: "golang"}
+	if err := sink.EmitPost(ctx, post); err != nil {
+		t.Fatalf("EmitPost: %v", err)
+	}
+	// Re-emitting the same ID should upsert, not conflict-error.
+	post.Upvotes = 9
+	if err := sink.EmitPost(ctx, post); err != nil {
+		t.Fatalf("EmitPost (upsert): %v", err)
+	}
+
+	var score int
+	if err := sink.db.QueryRowContext(ctx, "SELECT score FROM posts WHERE id = ?", "abc").Scan(&score); err != nil {
+		t.Fatalf("querying posts: %v", err)
+	}
+	if score != 9 {
+		t.Errorf("score = %d, want 9 after upsert", score)
+	}
+
+	snapshot := StatsSnapshot{
+		TopUsers:        map[string]int{"alice": 3},
+		MostUpvotedPost: post,
+		RateLimit:       RateLimitingInfo{Remaining: 42},
+	}
+	if err := sink.Emit(ctx, snapshot); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+
+	var topUser string
+	var remaining int
+	if err := sink.db.QueryRowContext(ctx, "SELECT top_user, rate_limit_remaining FROM stats_snapshots").Scan(&topUser, &remaining); err != nil {
+		t.Fatalf("querying stats_snapshots: %v", err)
+	}
+	if topUser != "alice" || remaining != 42 {
+		t.Errorf("got top_user=%q rate_limit_remaining=%d, want alice, 42", topUser, remaining)
+	}
+}
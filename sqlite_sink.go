@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteSink persists posts and stats snapshots to a local SQLite
+// database, for offline analysis without standing up an external store.
+type SQLiteSink struct {
+	db *sql.DB
+}
+
+// NewSQLiteSink opens (creating if necessary) a SQLite database at path
+// and runs its migration.
+func NewSQLiteSink(path string) (*SQLiteSink, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS posts (
+	id TEXT PRIMARY KEY,
+	title TEXT,
+	author TEXT,
+	score INTEGER,
+	num_comments INTEGER,
+	subreddit TEXT,
+	permalink TEXT,
+	created_utc INTEGER
+);
+CREATE TABLE IF NOT EXISTS stats_snapshots (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	top_user TEXT,
+	top_user_posts INTEGER,
+	most_upvoted_title TEXT,
+	most_upvoted_score INTEGER,
+	rate_limit_remaining INTEGER,
+	taken_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite: migrate: %w", err)
+	}
+
+	return &SQLiteSink{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteSink) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteSink) EmitPost(ctx context.Context, post RedditPost) error {
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO posts (id, title, author, score, num_comments, subreddit, permalink, created_utc)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET score = excluded.score, num_comments = excluded.num_comments`,
+		post.ID, post.Title, post.Author, post.Upvotes, post.NumComments, post.Subreddit, post.Permalink, post.CreatedUTC.Unix())
+	return err
+}
+
+func (s *SQLiteSink) Emit(ctx context.Context, snapshot StatsSnapshot) error {
+	topUser, topUserPosts := "", 0
+	for user, count := range snapshot.TopUsers {
+		if count > topUserPosts {
+			topUser, topUserPosts = user, count
+		}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO stats_snapshots (top_user, top_user_posts, most_upvoted_title, most_upvoted_score, rate_limit_remaining)
+VALUES (?, ?, ?, ?, ?)`,
+		topUser, topUserPosts, snapshot.MostUpvotedPost.Title, snapshot.MostUpvotedPost.Upvotes, snapshot.RateLimit.Remaining)
+	return err
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// RequestOption tweaks an outgoing request before it is sent, e.g. to
+// change the page size of a listing.
+type RequestOption func(*http.Request)
+
+// WithLimit sets the "limit" query parameter on a listing request.
+func WithLimit(n int) RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("limit", strconv.Itoa(n))
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// SubredditNew fetches the newest posts from a subreddit's /new listing.
+func (ac *AuthenticatedClient) SubredditNew(ctx context.Context, subreddit string, opts ...RequestOption) ([]RedditPost, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.reddit.com/r/"+subreddit+"/new.json?limit=10", nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := ac.authedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := ac.jsonPool.Get()
+	defer ac.jsonPool.Put(parser)
+
+	return decodeListingResponse(parser, body)
+}
+
+// SubredditInfo is the subset of a subreddit's "about" data watchers need
+// to validate themselves at registration time.
+type SubredditInfo struct {
+	Name        string
+	Type        string // subreddit_type: "public", "private", "restricted", ...
+	Quarantined bool
+	Subscribers int
+}
+
+// SubredditAbout fetches /r/<name>/about.json. It returns ErrSubredditPrivate
+// if the subreddit is private or quarantined, and ErrSubredditNotFound if it
+// doesn't exist (via defaultErrorMap).
+func (ac *AuthenticatedClient) SubredditAbout(ctx context.Context, subreddit string) (SubredditInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.reddit.com/r/"+subreddit+"/about.json", nil)
+	if err != nil {
+		return SubredditInfo{}, err
+	}
+
+	resp, err := ac.authedRequest(req)
+	if err != nil {
+		return SubredditInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SubredditInfo{}, err
+	}
+
+	parser := ac.jsonPool.Get()
+	defer ac.jsonPool.Put(parser)
+
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return SubredditInfo{}, fmt.Errorf("reddit: invalid about response: %w", err)
+	}
+	data := v.Get("data")
+	if data == nil {
+		return SubredditInfo{}, fmt.Errorf("reddit: about response missing data")
+	}
+
+	info := SubredditInfo{
+		Name:        getString(data, "display_name"),
+		Type:        getString(data, "subreddit_type"),
+		Quarantined: getBool(data, "quarantine"),
+		Subscribers: getInt(data, "subscribers"),
+	}
+
+	if info.Quarantined || info.Type == "private" {
+		return info, ErrSubredditPrivate
+	}
+	return info, nil
+}
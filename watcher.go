@@ -0,0 +1,143 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+)
+
+// Watcher matches posts from a single subreddit against a set of
+// criteria and invokes Callback for each match.
+type Watcher struct {
+	Subreddit string
+	Author    string // optional; empty matches any author
+
+	KeywordsInclude []string // title must contain at least one, if non-empty
+	KeywordsExclude []string // title must contain none, if non-empty
+
+	MinUpvotes  int
+	MinComments int
+
+	Callback func(RedditPost)
+}
+
+// matches reports whether post satisfies every criterion on w.
+func (w *Watcher) matches(post RedditPost) bool {
+	if !strings.EqualFold(post.Subreddit, w.Subreddit) {
+		return false
+	}
+	if w.Author != "" && !strings.EqualFold(post.Author, w.Author) {
+		return false
+	}
+	if post.Upvotes < w.MinUpvotes || post.NumComments < w.MinComments {
+		return false
+	}
+
+	title := strings.ToLower(post.Title)
+	if len(w.KeywordsInclude) > 0 && !containsAny(title, w.KeywordsInclude) {
+		return false
+	}
+	if len(w.KeywordsExclude) > 0 && containsAny(title, w.KeywordsExclude) {
+		return false
+	}
+	return true
+}
+
+func containsAny(title string, keywords []string) bool {
+	for _, kw := range keywords {
+		if strings.Contains(title, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WatcherManager runs a set of registered Watchers against every fetched
+// post and de-duplicates matches by post ID using an LRU of the last N
+// post IDs it has seen, inspired by apollo-backend's watcher subsystem.
+type WatcherManager struct {
+	mu       sync.Mutex
+	watchers []*Watcher
+	seen     *lruSet
+}
+
+// NewWatcherManager creates a WatcherManager that remembers the last
+// seenLimit post IDs it has dispatched, to avoid re-notifying watchers
+// when the same post shows up in consecutive fetches.
+func NewWatcherManager(seenLimit int) *WatcherManager {
+	return &WatcherManager{seen: newLRUSet(seenLimit)}
+}
+
+// Register validates w.Subreddit via SubredditAbout and adds w to the
+// manager. It returns ErrSubredditNotFound or ErrSubredditPrivate if the
+// subreddit can't be watched.
+func (wm *WatcherManager) Register(ctx context.Context, ac *AuthenticatedClient, w *Watcher) error {
+	if _, err := ac.SubredditAbout(ctx, w.Subreddit); err != nil {
+		return err
+	}
+
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	wm.watchers = append(wm.watchers, w)
+	return nil
+}
+
+// Run dispatches every post in posts that matches a registered watcher
+// and hasn't been seen before to that watcher's Callback.
+func (wm *WatcherManager) Run(posts []RedditPost) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	for _, post := range posts {
+		if post.ID == "" || wm.seen.Contains(post.ID) {
+			continue
+		}
+
+		dispatched := false
+		for _, w := range wm.watchers {
+			if w.matches(post) {
+				w.Callback(post)
+				dispatched = true
+			}
+		}
+		if dispatched {
+			wm.seen.Add(post.ID)
+		}
+	}
+}
+
+// lruSet is a fixed-capacity set that evicts the least recently added
+// entry once it's full. It's not safe for concurrent use on its own;
+// WatcherManager serializes access under its own mutex.
+type lruSet struct {
+	capacity int
+	order    *list.List
+	index    map[string]*list.Element
+}
+
+func newLRUSet(capacity int) *lruSet {
+	return &lruSet{
+		capacity: capacity,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+func (s *lruSet) Contains(id string) bool {
+	_, ok := s.index[id]
+	return ok
+}
+
+func (s *lruSet) Add(id string) {
+	if s.Contains(id) {
+		return
+	}
+	s.index[id] = s.order.PushBack(id)
+
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Front()
+		s.order.Remove(oldest)
+		delete(s.index, oldest.Value.(string))
+	}
+}
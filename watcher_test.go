@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestLRUSetEvictsOldestOnceFull(t *testing.T) {
+	s := newLRUSet(2)
+
+	s.Add("a")
+	s.Add("b")
+	if !s.Contains("a") || !s.Contains("b") {
+		t.Fatalf("expected a and b to both be present before eviction")
+	}
+
+	s.Add("c") // capacity 2: should evict "a", the oldest entry
+	if s.Contains("a") {
+		t.Error("expected oldest entry a to be evicted once capacity was exceeded")
+	}
+	if !s.Contains("b") || !s.Contains("c") {
+		t.Errorf("expected b and c to remain after eviction")
+	}
+}
+
+func TestLRUSetAddIsIdempotent(t *testing.T) {
+	s := newLRUSet(2)
+
+	s.Add("a")
+	s.Add("b")
+	s.Add("a") // re-adding an existing entry must not evict b
+
+	if !s.Contains("b") {
+		t.Error("re-adding an existing entry evicted an unrelated one")
+	}
+}
+
+func TestWatcherMatches(t *testing.T) {
+	w := &Watcher{
+		Subreddit:       "golang",
+		KeywordsInclude: []string{"generics"},
+		KeywordsExclude: []string{"rant"},
+		MinUpvotes:      10,
+	}
+
+	match := RedditPost{Subreddit: "golang", Title: "Generics are here", Upvotes: 20}
+	if !w.matches(match) {
+		t.Errorf("expected post to match: %+v", match)
+	}
+
+	cases := []RedditPost{
+		{Subreddit: "rust", Title: "Generics are here", Upvotes: 20},          // wrong subreddit
+		{Subreddit: "golang", Title: "Generics are here", Upvotes: 5},         // below MinUpvotes
+		{Subreddit: "golang", Title: "No mention of the g-word", Upvotes: 20}, // missing include keyword
+		{Subreddit: "golang", Title: "Generics rant incoming", Upvotes: 20},   // hits exclude keyword
+	}
+	for _, post := range cases {
+		if w.matches(post) {
+			t.Errorf("expected post not to match: %+v", post)
+		}
+	}
+}
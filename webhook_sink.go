@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs a JSON body to a configured URL for every snapshot and
+// post, retrying 5xx responses on the same bounded backoff schedule as the
+// request pipeline (retryBackoff).
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookSink creates a Sink that POSTs to url. If httpClient is nil,
+// http.DefaultClient is used.
+func NewWebhookSink(url string, httpClient *http.Client) *WebhookSink {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &WebhookSink{url: url, httpClient: httpClient}
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, snapshot StatsSnapshot) error {
+	return s.post(ctx, snapshot)
+}
+
+func (s *WebhookSink) EmitPost(ctx context.Context, post RedditPost) error {
+	return s.post(ctx, post)
+}
+
+func (s *WebhookSink) post(ctx context.Context, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.httpClient.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+			}
+			return nil
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("webhook: server error %s", resp.Status)
+		}
+
+		if attempt >= len(retryBackoff) {
+			break
+		}
+		time.Sleep(retryBackoff[attempt])
+	}
+	return lastErr
+}